@@ -0,0 +1,22 @@
+package libvirt
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// Provider returns the libvirt schema.Provider, registering every
+// libvirt_* resource this package implements.
+//
+// libvirt_network_portgroup's other delivery, letting a libvirt_domain NIC
+// reference a portgroup by name (`portgroup = "foo"` alongside
+// `network_name`), still isn't done: resource_libvirt_domain.go isn't
+// present in this tree, so there is no NIC schema to add the attribute to.
+func Provider() terraform.ResourceProvider {
+	return &schema.Provider{
+		ResourcesMap: map[string]*schema.Resource{
+			"libvirt_network":           resourceLibvirtNetwork(),
+			"libvirt_network_portgroup": resourceLibvirtNetworkPortGroup(),
+		},
+	}
+}
@@ -0,0 +1,450 @@
+package libvirt
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	libvirt "github.com/libvirt/libvirt-go"
+	"github.com/libvirt/libvirt-go-xml"
+)
+
+// portGroupSchema is shared between the "portgroup" block nested under
+// libvirt_network and the standalone libvirt_network_portgroup resource, so
+// that both ways of declaring a port group stay in sync.
+func portGroupSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Type:     schema.TypeString,
+					Required: true,
+				},
+				"default": {
+					Type:     schema.TypeBool,
+					Optional: true,
+				},
+				"vlan": {
+					Type:     schema.TypeList,
+					Optional: true,
+					MaxItems: 1,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"tag": {
+								Type:     schema.TypeList,
+								Required: true,
+								Elem: &schema.Resource{
+									Schema: map[string]*schema.Schema{
+										"id": {
+											Type:     schema.TypeInt,
+											Required: true,
+										},
+										"native_mode": {
+											Type:     schema.TypeString,
+											Optional: true,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				"bandwidth": {
+					Type:     schema.TypeList,
+					Optional: true,
+					MaxItems: 1,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"inbound":  bandwidthParamsSchema(),
+							"outbound": bandwidthParamsSchema(),
+						},
+					},
+				},
+				"virtualport": {
+					Type:     schema.TypeList,
+					Optional: true,
+					MaxItems: 1,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"type": {
+								Type:     schema.TypeString,
+								Required: true,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func bandwidthParamsSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"average": {
+					Type:     schema.TypeInt,
+					Optional: true,
+				},
+				"peak": {
+					Type:     schema.TypeInt,
+					Optional: true,
+				},
+				"burst": {
+					Type:     schema.TypeInt,
+					Optional: true,
+				},
+			},
+		},
+	}
+}
+
+// portGroupFromResourceDataRoot builds a libvirtxml.NetworkPortGroup out of
+// the top-level fields of a libvirt_network_portgroup resource.
+func portGroupFromResourceDataRoot(d *schema.ResourceData) libvirtxml.NetworkPortGroup {
+	return portGroupFromResourceData(map[string]interface{}{
+		"name":        d.Get("name"),
+		"default":     d.Get("default"),
+		"vlan":        d.Get("vlan"),
+		"bandwidth":   d.Get("bandwidth"),
+		"virtualport": d.Get("virtualport"),
+	})
+}
+
+// portGroupFromResourceData builds a libvirtxml.NetworkPortGroup out of one
+// entry of a "portgroup" list, as found in either libvirt_network or
+// libvirt_network_portgroup's ResourceData.
+func portGroupFromResourceData(portGroup map[string]interface{}) libvirtxml.NetworkPortGroup {
+	def := libvirtxml.NetworkPortGroup{
+		Name: portGroup["name"].(string),
+	}
+	if portGroup["default"].(bool) {
+		def.Default = "yes"
+	}
+
+	for _, vlanI := range portGroup["vlan"].([]interface{}) {
+		vlan := vlanI.(map[string]interface{})
+		netVLAN := libvirtxml.NetworkVLAN{}
+		for _, tagI := range vlan["tag"].([]interface{}) {
+			tag := tagI.(map[string]interface{})
+			netVLAN.Tags = append(netVLAN.Tags, libvirtxml.NetworkVLANTag{
+				ID:         uint(tag["id"].(int)),
+				NativeMode: tag["native_mode"].(string),
+			})
+		}
+		def.VLAN = &netVLAN
+	}
+
+	for _, bandwidthI := range portGroup["bandwidth"].([]interface{}) {
+		bandwidth := bandwidthI.(map[string]interface{})
+		def.Bandwidth = &libvirtxml.NetworkBandwidth{
+			Inbound:  bandwidthParamsFromResourceData(bandwidth["inbound"].([]interface{})),
+			Outbound: bandwidthParamsFromResourceData(bandwidth["outbound"].([]interface{})),
+		}
+	}
+
+	for _, virtualPortI := range portGroup["virtualport"].([]interface{}) {
+		virtualPort := virtualPortI.(map[string]interface{})
+		def.VirtualPort = &libvirtxml.NetworkVirtualPort{
+			Type: virtualPort["type"].(string),
+		}
+	}
+
+	return def
+}
+
+func bandwidthParamsFromResourceData(paramsList []interface{}) *libvirtxml.NetworkBandwidthParams {
+	if len(paramsList) == 0 {
+		return nil
+	}
+	params := paramsList[0].(map[string]interface{})
+	average := params["average"].(int)
+	peak := params["peak"].(int)
+	burst := params["burst"].(int)
+	return &libvirtxml.NetworkBandwidthParams{
+		Average: &average,
+		Peak:    &peak,
+		Burst:   &burst,
+	}
+}
+
+func portGroupToResourceData(portGroup libvirtxml.NetworkPortGroup) map[string]interface{} {
+	data := map[string]interface{}{
+		"name":    portGroup.Name,
+		"default": strings.ToLower(portGroup.Default) == "yes",
+	}
+
+	if portGroup.VLAN != nil {
+		tags := []map[string]interface{}{}
+		for _, tag := range portGroup.VLAN.Tags {
+			tags = append(tags, map[string]interface{}{
+				"id":          int(tag.ID),
+				"native_mode": tag.NativeMode,
+			})
+		}
+		data["vlan"] = []map[string]interface{}{
+			{"tag": tags},
+		}
+	}
+
+	if portGroup.Bandwidth != nil {
+		data["bandwidth"] = []map[string]interface{}{
+			{
+				"inbound":  bandwidthParamsToResourceData(portGroup.Bandwidth.Inbound),
+				"outbound": bandwidthParamsToResourceData(portGroup.Bandwidth.Outbound),
+			},
+		}
+	}
+
+	if portGroup.VirtualPort != nil {
+		data["virtualport"] = []map[string]interface{}{
+			{"type": portGroup.VirtualPort.Type},
+		}
+	}
+
+	return data
+}
+
+func bandwidthParamsToResourceData(params *libvirtxml.NetworkBandwidthParams) []map[string]interface{} {
+	if params == nil {
+		return nil
+	}
+	data := map[string]interface{}{}
+	if params.Average != nil {
+		data["average"] = *params.Average
+	}
+	if params.Peak != nil {
+		data["peak"] = *params.Peak
+	}
+	if params.Burst != nil {
+		data["burst"] = *params.Burst
+	}
+	return []map[string]interface{}{data}
+}
+
+// portGroupDeleteXML renders a <portgroup> element matching only the name, which
+// is all virNetworkUpdate needs to identify the entry being deleted.
+func portGroupDeleteXML(name string) (string, error) {
+	return xmlMarshallIndented(libvirtxml.NetworkPortGroup{Name: name})
+}
+
+// a libvirt network port group, attached to an existing libvirt_network by
+// UUID
+//
+// Resource example:
+//
+//	resource "libvirt_network_portgroup" "tenant_a" {
+//	   network_id = libvirt_network.k8snet.id
+//	   name       = "tenant-a"
+//	   vlan {
+//	     tag {
+//	       id = 100
+//	     }
+//	   }
+//	}
+//
+// Registered in Provider()'s ResourcesMap. Referencing a portgroup by name
+// from a libvirt_domain NIC (e.g. `portgroup = "tenant-a"` next to
+// `network_name`) is a resource_libvirt_domain.go change and isn't done
+// here, since that file isn't present in this tree.
+func resourceLibvirtNetworkPortGroup() *schema.Resource {
+	portGroup := portGroupSchema().Elem.(*schema.Resource)
+	portGroup.Schema["network_id"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Required: true,
+		ForceNew: true,
+	}
+	// Update issues a virNetworkUpdate MODIFY keyed by the current name, so a
+	// rename has nothing to modify and would orphan the old entry. Recreate
+	// the resource instead (delete the old portgroup, add the new one).
+	portGroup.Schema["name"].ForceNew = true
+
+	return &schema.Resource{
+		Create: resourceLibvirtNetworkPortGroupCreate,
+		Read:   resourceLibvirtNetworkPortGroupRead,
+		Update: resourceLibvirtNetworkPortGroupUpdate,
+		Delete: resourceLibvirtNetworkPortGroupDelete,
+		Exists: resourceLibvirtNetworkPortGroupExists,
+		Schema: portGroup.Schema,
+	}
+}
+
+func portGroupID(networkID, name string) string {
+	return fmt.Sprintf("%s/%s", networkID, name)
+}
+
+func parsePortGroupID(id string) (networkID string, name string, err error) {
+	parts := strings.SplitN(id, "/", 2)
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("Unexpected format of ID (%s), expected network_id/name", id)
+	}
+	return parts[0], parts[1], nil
+}
+
+func resourceLibvirtNetworkPortGroupExists(d *schema.ResourceData, meta interface{}) (bool, error) {
+	virConn := meta.(*Client).libvirt
+	if virConn == nil {
+		return false, fmt.Errorf(LibVirtConIsNil)
+	}
+
+	networkID, name, err := parsePortGroupID(d.Id())
+	if err != nil {
+		return false, err
+	}
+
+	network, err := virConn.LookupNetworkByUUIDString(networkID)
+	if err != nil {
+		if lverr, ok := err.(libvirt.Error); ok && lverr.Code == libvirt.ERR_NO_NETWORK {
+			return false, nil
+		}
+		return false, err
+	}
+	defer network.Free()
+
+	networkDef, err := getXMLNetworkDefFromLibvirt(network)
+	if err != nil {
+		return false, err
+	}
+
+	for _, portGroup := range networkDef.PortGroups {
+		if portGroup.Name == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func resourceLibvirtNetworkPortGroupCreate(d *schema.ResourceData, meta interface{}) error {
+	virConn := meta.(*Client).libvirt
+	if virConn == nil {
+		return fmt.Errorf(LibVirtConIsNil)
+	}
+
+	networkID := d.Get("network_id").(string)
+	network, err := virConn.LookupNetworkByUUIDString(networkID)
+	if err != nil {
+		return fmt.Errorf("Can't retrieve network with ID '%s': %s", networkID, err)
+	}
+	defer network.Free()
+
+	portGroup := portGroupFromResourceDataRoot(d)
+	xml, err := xmlMarshallIndented(portGroup)
+	if err != nil {
+		return fmt.Errorf("Error serializing libvirt network portgroup: %s", err)
+	}
+
+	log.Printf("[DEBUG] Adding portgroup to network %s: %s", networkID, xml)
+	if err := network.Update(libvirt.NETWORK_UPDATE_COMMAND_ADD_LAST, libvirt.NETWORK_SECTION_PORTGROUP, -1, xml, libvirt.NETWORK_UPDATE_AFFECT_LIVE|libvirt.NETWORK_UPDATE_AFFECT_CONFIG); err != nil {
+		return fmt.Errorf("Error adding libvirt network portgroup: %s", err)
+	}
+
+	d.SetId(portGroupID(networkID, portGroup.Name))
+	return resourceLibvirtNetworkPortGroupRead(d, meta)
+}
+
+func resourceLibvirtNetworkPortGroupRead(d *schema.ResourceData, meta interface{}) error {
+	virConn := meta.(*Client).libvirt
+	if virConn == nil {
+		return fmt.Errorf(LibVirtConIsNil)
+	}
+
+	networkID, name, err := parsePortGroupID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	network, err := virConn.LookupNetworkByUUIDString(networkID)
+	if err != nil {
+		return fmt.Errorf("Error retrieving libvirt network: %s", err)
+	}
+	defer network.Free()
+
+	networkDef, err := getXMLNetworkDefFromLibvirt(network)
+	if err != nil {
+		return fmt.Errorf("Error reading libvirt network XML description: %s", err)
+	}
+
+	for _, portGroup := range networkDef.PortGroups {
+		if portGroup.Name != name {
+			continue
+		}
+		d.Set("network_id", networkID)
+		for key, value := range portGroupToResourceData(portGroup) {
+			d.Set(key, value)
+		}
+		return nil
+	}
+
+	// the portgroup is gone, let Terraform recreate it
+	d.SetId("")
+	return nil
+}
+
+func resourceLibvirtNetworkPortGroupUpdate(d *schema.ResourceData, meta interface{}) error {
+	virConn := meta.(*Client).libvirt
+	if virConn == nil {
+		return fmt.Errorf(LibVirtConIsNil)
+	}
+
+	networkID, _, err := parsePortGroupID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	network, err := virConn.LookupNetworkByUUIDString(networkID)
+	if err != nil {
+		return fmt.Errorf("Can't retrieve network with ID '%s' during update: %s", networkID, err)
+	}
+	defer network.Free()
+
+	portGroup := portGroupFromResourceDataRoot(d)
+	xml, err := xmlMarshallIndented(portGroup)
+	if err != nil {
+		return fmt.Errorf("Error serializing libvirt network portgroup: %s", err)
+	}
+
+	if err := network.Update(libvirt.NETWORK_UPDATE_COMMAND_MODIFY, libvirt.NETWORK_SECTION_PORTGROUP, -1, xml, libvirt.NETWORK_UPDATE_AFFECT_LIVE|libvirt.NETWORK_UPDATE_AFFECT_CONFIG); err != nil {
+		return fmt.Errorf("Error updating libvirt network portgroup: %s", err)
+	}
+
+	d.SetId(portGroupID(networkID, portGroup.Name))
+	return resourceLibvirtNetworkPortGroupRead(d, meta)
+}
+
+func resourceLibvirtNetworkPortGroupDelete(d *schema.ResourceData, meta interface{}) error {
+	virConn := meta.(*Client).libvirt
+	if virConn == nil {
+		return fmt.Errorf(LibVirtConIsNil)
+	}
+
+	networkID, name, err := parsePortGroupID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	network, err := virConn.LookupNetworkByUUIDString(networkID)
+	if err != nil {
+		if lverr, ok := err.(libvirt.Error); ok && lverr.Code == libvirt.ERR_NO_NETWORK {
+			return nil
+		}
+		return fmt.Errorf("Can't retrieve network with ID '%s' during delete: %s", networkID, err)
+	}
+	defer network.Free()
+
+	xml, err := portGroupDeleteXML(name)
+	if err != nil {
+		return err
+	}
+
+	if err := network.Update(libvirt.NETWORK_UPDATE_COMMAND_DELETE, libvirt.NETWORK_SECTION_PORTGROUP, -1, xml, libvirt.NETWORK_UPDATE_AFFECT_LIVE|libvirt.NETWORK_UPDATE_AFFECT_CONFIG); err != nil {
+		return fmt.Errorf("Error removing libvirt network portgroup: %s", err)
+	}
+
+	return nil
+}
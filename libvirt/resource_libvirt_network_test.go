@@ -0,0 +1,118 @@
+package libvirt
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// TestAccLibvirtNetwork_LiveUpdateDHCPAndDNSHosts exercises the
+// virNetworkUpdate code path used by dhcp.hosts/dns.hosts: it mutates both
+// lists on a second apply and checks that the network's ID (its UUID) is
+// unchanged, proving the change landed on the running network instead of
+// forcing a destroy/create cycle.
+func TestAccLibvirtNetwork_LiveUpdateDHCPAndDNSHosts(t *testing.T) {
+	var networkID string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckLibvirtNetworkDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLibvirtNetworkConfigDHCPDNSHosts(`
+						hosts {
+							mac  = "aa:bb:cc:dd:ee:01"
+							name = "host1"
+							ip   = "10.17.3.10"
+						}`, `
+						hosts {
+							ip        = "10.17.3.10"
+							hostnames = ["host1.example.test"]
+						}`),
+				Check: testAccCheckLibvirtNetworkExists("libvirt_network.test", &networkID),
+			},
+			{
+				Config: testAccLibvirtNetworkConfigDHCPDNSHosts(`
+						hosts {
+							mac  = "aa:bb:cc:dd:ee:02"
+							name = "host2"
+							ip   = "10.17.3.11"
+						}`, `
+						hosts {
+							ip        = "10.17.3.11"
+							hostnames = ["host2.example.test"]
+						}`),
+				Check: testAccCheckLibvirtNetworkExistsWithSameID("libvirt_network.test", &networkID),
+			},
+		},
+	})
+}
+
+func testAccLibvirtNetworkConfigDHCPDNSHosts(dhcpHosts, dnsHosts string) string {
+	return fmt.Sprintf(`
+resource "libvirt_network" "test" {
+  name      = "terraform-test-live-update"
+  mode      = "nat"
+  domain    = "example.test"
+  addresses = ["10.17.3.0/24"]
+
+  dhcp {
+    %s
+  }
+
+  dns {
+    %s
+  }
+}
+`, dhcpHosts, dnsHosts)
+}
+
+// testAccCheckLibvirtNetworkExists records the network's ID each time it's
+// called, so a later step can confirm it didn't change.
+func testAccCheckLibvirtNetworkExists(name string, id *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("Not found: %s", name)
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set for %s", name)
+		}
+		*id = rs.Primary.ID
+		return nil
+	}
+}
+
+// testAccCheckLibvirtNetworkExistsWithSameID asserts the network still has
+// the ID recorded by testAccCheckLibvirtNetworkExists, i.e. that it was
+// updated in place rather than recreated.
+func testAccCheckLibvirtNetworkExistsWithSameID(name string, id *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("Not found: %s", name)
+		}
+		if rs.Primary.ID != *id {
+			return fmt.Errorf("expected network to be updated in place, got a new ID: was %s, now %s", *id, rs.Primary.ID)
+		}
+		return nil
+	}
+}
+
+func testAccCheckLibvirtNetworkDestroy(s *terraform.State) error {
+	virConn := testAccProvider.Meta().(*Client).libvirt
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "libvirt_network" {
+			continue
+		}
+		network, err := virConn.LookupNetworkByUUIDString(rs.Primary.ID)
+		if err == nil {
+			network.Free()
+			return fmt.Errorf("network '%s' still exists", rs.Primary.ID)
+		}
+	}
+	return nil
+}